@@ -1,10 +1,16 @@
 package factory
 
 import (
+	"net/http"
+
+	"github.com/devec0/kvsql/server"
 	restful "github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
 )
 
-type Rest struct{}
+type Rest struct {
+	Server *server.Server
+}
 
 func (r Rest) Install(c *restful.Container) {
 	ws := new(restful.WebService)
@@ -12,6 +18,24 @@ func (r Rest) Install(c *restful.Container) {
 	ws.Doc("dqlite cluster management")
 	ws.Route(ws.GET("/").To(getHandler))
 	c.Add(ws)
+
+	// The routes below query the server, so they are only available when
+	// one is set.
+	if r.Server == nil {
+		return
+	}
+
+	cluster := new(restful.WebService)
+	cluster.Path("/cluster").Produces(restful.MIME_JSON)
+	cluster.Doc("dqlite cluster topology")
+	cluster.Route(cluster.GET("/members").To(r.getMembersHandler))
+	c.Add(cluster)
+
+	status := new(restful.WebService)
+	status.Path("/status").Produces(restful.MIME_JSON)
+	status.Doc("kvsql node status")
+	status.Route(status.GET("").To(r.getStatusHandler))
+	c.Add(status)
 }
 
 func getHandler(req *restful.Request, resp *restful.Response) {
@@ -24,3 +48,29 @@ func getHandler(req *restful.Request, resp *restful.Response) {
 	}
 	resp.WriteEntity(foo)
 }
+
+func (r Rest) getMembersHandler(req *restful.Request, resp *restful.Response) {
+	members, err := r.Server.ListMembers(req.Request.Context())
+	if err != nil {
+		resp.WriteErrorString(errorStatus(err), err.Error())
+		return
+	}
+	resp.WriteEntity(members)
+}
+
+func (r Rest) getStatusHandler(req *restful.Request, resp *restful.Response) {
+	status, err := r.Server.Status(req.Request.Context())
+	if err != nil {
+		resp.WriteErrorString(http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.WriteEntity(status)
+}
+
+// Return the HTTP status code to reply with for the given server error.
+func errorStatus(err error) int {
+	if errors.Cause(err) == server.ErrNoLeader {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,78 @@
+package factory_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	factory "github.com/devec0/kvsql"
+	"github.com/devec0/kvsql/server"
+	"github.com/devec0/kvsql/server/config"
+	restful "github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRest_ClusterMembers(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	c := restful.NewContainer()
+	factory.Rest{Server: s}.Install(c)
+
+	req := httptest.NewRequest(http.MethodGet, "/cluster/members", nil)
+	resp := httptest.NewRecorder()
+	c.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	members := []map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &members))
+	require.Len(t, members, 1)
+
+	member := members[0]
+	assert.Len(t, member, 3)
+	assert.NotZero(t, member["id"])
+	assert.Equal(t, "localhost:9991", member["address"])
+	assert.Equal(t, "leader", member["role"])
+}
+
+func TestRest_ClusterMembers_NoLeader(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	c := restful.NewContainer()
+	factory.Rest{Server: s}.Install(c)
+
+	// With the request context already done the leader can't be reached.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/cluster/members", nil).WithContext(ctx)
+	resp := httptest.NewRecorder()
+	c.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
+
+func TestRest_NoServer(t *testing.T) {
+	c := restful.NewContainer()
+	factory.Rest{}.Install(c)
+
+	for _, path := range []string{"/cluster/members", "/status"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		c.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusNotFound, resp.Code, path)
+	}
+}
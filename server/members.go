@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+
+	"github.com/canonical/go-dqlite/client"
+	"github.com/pkg/errors"
+)
+
+// MemberInfo describes a single node of the dqlite cluster.
+type MemberInfo struct {
+	ID      uint64 `json:"id"`
+	Address string `json:"address"`
+	Role    string `json:"role"` // One of leader, voter, standby or spare
+}
+
+// ListMembers returns the current dqlite cluster topology, as seen by the
+// leader. The whole call is bounded by leaderTimeout, and ErrNoLeader is
+// returned if the leader can't be reached.
+func (s *Server) ListMembers(ctx context.Context) ([]MemberInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, leaderTimeout)
+	defer cancel()
+
+	cli, err := s.leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	leader, err := cli.Leader(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get leader")
+	}
+
+	nodes, err := cli.Cluster(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get cluster")
+	}
+
+	members := make([]MemberInfo, len(nodes))
+	for i, node := range nodes {
+		members[i] = MemberInfo{
+			ID:      node.ID,
+			Address: node.Address,
+			Role:    memberRole(node, leader),
+		}
+	}
+
+	return members, nil
+}
+
+// Return the role label used in MemberInfo for the given node.
+func memberRole(node client.NodeInfo, leader *client.NodeInfo) string {
+	if leader != nil && node.ID == leader.ID {
+		return "leader"
+	}
+	switch node.Role {
+	case client.Voter:
+		return "voter"
+	case client.StandBy:
+		return "standby"
+	default:
+		return "spare"
+	}
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/canonical/go-dqlite/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemberRole(t *testing.T) {
+	leader := &client.NodeInfo{ID: 1, Address: "localhost:9991", Role: client.Voter}
+
+	cases := []struct {
+		node client.NodeInfo
+		role string
+	}{
+		{*leader, "leader"},
+		{client.NodeInfo{ID: 2, Role: client.Voter}, "voter"},
+		{client.NodeInfo{ID: 3, Role: client.StandBy}, "standby"},
+		{client.NodeInfo{ID: 4, Role: client.Spare}, "spare"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.role, memberRole(c.node, leader), c.node.ID)
+	}
+
+	// Without a known leader, voters are reported as such.
+	assert.Equal(t, "voter", memberRole(*leader, nil))
+}
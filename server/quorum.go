@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/canonical/go-dqlite/client"
+	"github.com/pkg/errors"
+)
+
+// Quorum reports whether a majority of the voting nodes in the cluster are
+// reachable and agree on the same leader.
+//
+// The voters are read from our local copy of the cluster configuration and,
+// if the leader answers in time, refreshed from the leader's view of the
+// cluster. The leader lookup gets at most half of the time left on ctx, so
+// that the probes always get to run even when there is no leader. Nodes are
+// then probed concurrently over the dqlite protocol. An unreachable node only
+// counts against the quorum, so (false, nil) means the quorum is lost, while
+// an error, including ctx expiring before the probes were done, means its
+// status could not be determined.
+func (s *Server) Quorum(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	nodes, err := s.localNodes(ctx)
+	if err != nil {
+		return false, err
+	}
+	if fresh, err := s.leaderNodes(ctx); err == nil {
+		nodes = fresh
+	}
+
+	return s.quorum(ctx, nodes)
+}
+
+// Return the cluster nodes as reported by the leader, giving up after half
+// of the time left on ctx or leaderTimeout, whichever comes first.
+func (s *Server) leaderNodes(ctx context.Context) ([]client.NodeInfo, error) {
+	timeout := leaderTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if half := time.Until(deadline) / 2; half < timeout {
+			timeout = half
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cli, err := s.leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	nodes, err := cli.Cluster(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get cluster")
+	}
+
+	return nodes, nil
+}
+
+// Probe the voters among the given nodes and check whether a majority of
+// them agree on a leader.
+func (s *Server) quorum(ctx context.Context, nodes []client.NodeInfo) (bool, error) {
+	voters := []client.NodeInfo{}
+	for _, node := range nodes {
+		if node.Role == client.Voter {
+			voters = append(voters, node)
+		}
+	}
+	if len(voters) == 0 {
+		return false, fmt.Errorf("no voting nodes configured")
+	}
+
+	leaders := make([]uint64, len(voters))
+	var wg sync.WaitGroup
+	for i, node := range voters {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			leaders[i] = s.probeLeader(ctx, address)
+		}(i, node.Address)
+	}
+	wg.Wait()
+
+	votes := map[uint64]int{}
+	for _, id := range leaders {
+		if id == 0 {
+			continue
+		}
+		votes[id]++
+		if votes[id] > len(voters)/2 {
+			return true, nil
+		}
+	}
+
+	// Probes cut short by ctx say nothing about the nodes themselves.
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// Return the ID of the leader known to the node at the given address, or 0
+// if the node can't be reached within leaderTimeout or has no leader.
+func (s *Server) probeLeader(ctx context.Context, address string) uint64 {
+	ctx, cancel := context.WithTimeout(ctx, leaderTimeout)
+	defer cancel()
+
+	cli, err := client.New(ctx, address, client.WithDialFunc(s.dial))
+	if err != nil {
+		return 0
+	}
+	defer cli.Close()
+
+	leader, err := cli.Leader(ctx)
+	if err != nil || leader == nil {
+		return 0
+	}
+
+	return leader.ID
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/canonical/go-dqlite/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorum_NoVoters(t *testing.T) {
+	s := &Server{}
+	nodes := []client.NodeInfo{
+		{ID: 1, Address: "localhost:9991", Role: client.StandBy},
+		{ID: 2, Address: "localhost:9992", Role: client.Spare},
+	}
+
+	ok, err := s.quorum(context.Background(), nodes)
+	assert.EqualError(t, err, "no voting nodes configured")
+	assert.False(t, ok)
+}
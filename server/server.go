@@ -18,12 +18,25 @@ import (
 	log "k8s.io/klog/v2"
 )
 
+// Name of the dqlite database holding the kine data.
+const database = "k8s"
+
+// How long to wait when connecting to the dqlite leader. The app keeps
+// retrying until its context is done, so without a bound callers would hang
+// for as long as the cluster has no leader.
+const leaderTimeout = 5 * time.Second
+
+// ErrNoLeader is returned when the dqlite leader can't be reached.
+var ErrNoLeader = fmt.Errorf("no leader reachable")
+
 // Server sets up a single dqlite node and serves the cluster management API.
 type Server struct {
 	dir        string // Data directory
 	address    string // Network address
 	app        *app.App
+	dial       client.DialFunc // TLS dialer for reaching other nodes
 	cancelKine context.CancelFunc
+	started    time.Time // When the server finished starting
 }
 
 func New(dir string) (*Server, error) {
@@ -79,6 +92,8 @@ func New(dir string) (*Server, error) {
 		app.WithFailureDomain(cfg.FailureDomain),
 	}
 
+	dial := client.DialFuncWithTLS(client.DefaultDialFunc, app.SimpleDialTLSConfig(cfg.KeyPair, cfg.Pool))
+
 	// Possibly initialize our ID, address and initial node store content.
 	if cfg.Init != nil {
 		log.Info("Attempting to initialise the kvsql/kine/dqline cluster")
@@ -110,7 +125,7 @@ func New(dir string) (*Server, error) {
 	peers := filepath.Join(dir, "cluster.yaml")
 	config := endpoint.Config{
 	        Listener: "tcp://127.0.0.1:12379",
-		Endpoint: fmt.Sprintf("dqlite://k8s?peer-file=%s&driver-name=%s", peers, app.Driver()),
+		Endpoint: fmt.Sprintf("dqlite://%s?peer-file=%s&driver-name=%s", database, peers, app.Driver()),
 	}
 	
 	kineCtx, cancelKine := context.WithCancel(context.Background())
@@ -126,13 +141,43 @@ func New(dir string) (*Server, error) {
 		dir:        dir,
 		address:    cfg.Address,
 		app:        app,
+		dial:       dial,
 		cancelKine: cancelKine,
+		started:    time.Now(),
 	}
 
 	log.Infof("kvsql returning new server instance: %s", cfg.Address)
 	return s, nil
 }
 
+// Connect to the dqlite leader, giving up after leaderTimeout. Failures are
+// reported as ErrNoLeader.
+func (s *Server) leader(ctx context.Context) (*client.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, leaderTimeout)
+	defer cancel()
+
+	cli, err := s.app.Leader(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ErrNoLeader, "connect to leader: %v", err)
+	}
+	return cli, nil
+}
+
+// Return the nodes in our local copy of the cluster configuration. It is
+// refreshed periodically by the dqlite app, so it may be slightly stale, but
+// unlike asking the leader it always works.
+func (s *Server) localNodes(ctx context.Context) ([]client.NodeInfo, error) {
+	store, err := client.NewYamlNodeStore(filepath.Join(s.dir, "cluster.yaml"))
+	if err != nil {
+		return nil, errors.Wrap(err, "open node store")
+	}
+	nodes, err := store.Get(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get nodes")
+	}
+	return nodes, nil
+}
+
 func (s *Server) Close(ctx context.Context) error {
 	if s.cancelKine != nil {
 		log.Info("Cancelling kine context")
@@ -1,8 +1,11 @@
 package server_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -12,6 +15,8 @@ import (
 	"github.com/devec0/kvsql/server"
 	"github.com/devec0/kvsql/server/config"
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/etcd/clientv3"
 )
@@ -104,6 +109,263 @@ func TestNew_Update(t *testing.T) {
 	require.NoError(t, s.Close(context.Background()))
 }
 
+func TestListMembers(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	members, err := s.ListMembers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, "localhost:9991", members[0].Address)
+	assert.Equal(t, "leader", members[0].Role)
+}
+
+func TestStatus(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	cfg := clientv3.Config{Endpoints: []string{"localhost:12379"}}
+	client, err := clientv3.New(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+
+	before, err := s.Status(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, before.Error)
+
+	_, err = client.Put(context.Background(), "/foo", "bar")
+	require.NoError(t, err)
+
+	status, err := s.Status(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, status.Error)
+	assert.Equal(t, "localhost:9991", status.Address)
+	assert.NotZero(t, status.NodeID)
+	assert.True(t, status.Leader)
+	assert.Equal(t, 0, status.NumPeers)
+	assert.True(t, status.CurrentRevision > before.CurrentRevision)
+	assert.Equal(t, int64(1), status.KeyCount)
+}
+
+func TestStatus_NoLeader(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	// With the context already done the leader can't be reached, so only
+	// the local fields are reported.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	status, err := s.Status(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, status.Error)
+	assert.NotZero(t, status.NodeID)
+	assert.Equal(t, "localhost:9991", status.Address)
+	assert.False(t, status.Leader)
+	assert.Equal(t, 0, status.NumPeers)
+	assert.Zero(t, status.CurrentRevision)
+	assert.Zero(t, status.KeyCount)
+}
+
+func TestStatus_PeerDown(t *testing.T) {
+	init1 := &config.Init{Address: "localhost:9991"}
+	dir1, cleanup1 := newDirWithInit(t, init1)
+	defer cleanup1()
+
+	s1, err := server.New(dir1)
+	require.NoError(t, err)
+	defer closeWithTimeout(s1)
+
+	init2 := &config.Init{Address: "localhost:9992", Cluster: []string{"localhost:9991"}}
+	dir2, cleanup2 := newDirWithInit(t, init2)
+	defer cleanup2()
+
+	s2, err := server.New(dir2)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	s2.Close(ctx)
+
+	// Whether or not the leader is still reachable, the local fields are
+	// always reported and the call itself doesn't fail.
+	ctx, cancel = context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	status, err := s1.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:9991", status.Address)
+	assert.NotZero(t, status.NodeID)
+	assert.Equal(t, 1, status.NumPeers)
+}
+
+func TestQuorum(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	ok, err := s.Quorum(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// A context that is already done must not be mistaken for lost quorum.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok, err = s.Quorum(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, ok)
+}
+
+func TestQuorum_Lost(t *testing.T) {
+	init1 := &config.Init{Address: "localhost:9991"}
+	dir1, cleanup1 := newDirWithInit(t, init1)
+	defer cleanup1()
+
+	s1, err := server.New(dir1)
+	require.NoError(t, err)
+	defer closeWithTimeout(s1)
+
+	init2 := &config.Init{Address: "localhost:9992", Cluster: []string{"localhost:9991"}}
+	dir2, cleanup2 := newDirWithInit(t, init2)
+	defer cleanup2()
+
+	s2, err := server.New(dir2)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	s2.Close(ctx)
+
+	// Leave the probes enough time to actually reach both nodes.
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ok, err := s1.Quorum(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSnapshot(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	progress := make(chan int64)
+	done := make(chan int64)
+	go func() {
+		var last int64
+		for n := range progress {
+			last = n
+		}
+		done <- last
+	}()
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Snapshot(context.Background(), &buf, progress))
+	close(progress)
+	assert.Equal(t, int64(buf.Len()), <-done)
+
+	assert.Equal(t, []string{"k8s", "k8s-wal"}, readSnapshot(t, &buf))
+}
+
+func TestSnapshot_NoProgress(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Snapshot(context.Background(), &buf, nil))
+
+	assert.Equal(t, []string{"k8s", "k8s-wal"}, readSnapshot(t, &buf))
+}
+
+func TestSnapshot_CancelWhileReporting(t *testing.T) {
+	init := &config.Init{Address: "localhost:9991"}
+	dir, cleanup := newDirWithInit(t, init)
+	defer cleanup()
+
+	s, err := server.New(dir)
+	require.NoError(t, err)
+	defer s.Close(context.Background())
+
+	// Cancel after the first progress report and stop draining, so the
+	// next report can only be interrupted by the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	progress := make(chan int64)
+	go func() {
+		<-progress
+		cancel()
+	}()
+
+	var buf bytes.Buffer
+	err = s.Snapshot(ctx, &buf, progress)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+}
+
+// Close a server with a bounded context, since handover stalls if the
+// cluster has lost quorum.
+func closeWithTimeout(s *server.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	s.Close(ctx)
+}
+
+// Read a snapshot archive through to the end, checking that each entry has
+// as much data as its header says, and return the entry names.
+func readSnapshot(t *testing.T, r io.Reader) []string {
+	t.Helper()
+
+	names := []string{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		assert.Equal(t, header.Size, int64(len(data)), header.Name)
+		assert.WithinDuration(t, time.Now(), header.ModTime, time.Minute, header.Name)
+
+		names = append(names, header.Name)
+	}
+
+	return names
+}
+
 // Return a new temporary directory populated with the test cluster certificate
 // and an init.yaml file with the given content.
 func newDirWithInit(t *testing.T, init *config.Init) (string, func()) {
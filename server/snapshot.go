@@ -0,0 +1,73 @@
+package server
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Snapshot dumps the kine database from the current dqlite leader and writes
+// it to w as a tar archive, with one entry per dumped file (the database and
+// its WAL).
+//
+// If progress is not nil, the total number of bytes written so far is sent on
+// it after every write. Sends block, so the caller must keep draining the
+// channel until Snapshot returns.
+func (s *Server) Snapshot(ctx context.Context, w io.Writer, progress chan<- int64) error {
+	cli, err := s.app.Leader(ctx)
+	if err != nil {
+		return errors.Wrap(err, "connect to leader")
+	}
+	defer cli.Close()
+
+	files, err := cli.Dump(ctx, database)
+	if err != nil {
+		return errors.Wrap(err, "dump database")
+	}
+	dumped := time.Now()
+
+	tw := tar.NewWriter(&progressWriter{ctx: ctx, w: w, progress: progress})
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    file.Name,
+			Mode:    0600,
+			ModTime: dumped,
+			Size:    int64(len(file.Data)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return errors.Wrapf(err, "write %s header", file.Name)
+		}
+		if _, err := tw.Write(file.Data); err != nil {
+			return errors.Wrapf(err, "write %s", file.Name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "close archive")
+	}
+
+	return nil
+}
+
+// Wrap an io.Writer and report the running byte count on a channel.
+type progressWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	progress chan<- int64
+	written  int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.progress != nil && n > 0 {
+		select {
+		case p.progress <- p.written:
+		case <-p.ctx.Done():
+			return n, p.ctx.Err()
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ServerStatus summarizes the state of a kvsql node.
+type ServerStatus struct {
+	NodeID          uint64 `json:"node_id"`
+	Address         string `json:"address"`
+	Leader          bool   `json:"leader"`
+	NumPeers        int    `json:"num_peers"` // Other nodes in the cluster
+	CurrentRevision int64  `json:"current_revision"`
+	KeyCount        int64  `json:"key_count"`
+	UptimeSeconds   int64  `json:"uptime_seconds"`
+	Error           string `json:"error,omitempty"` // Why some fields are missing
+}
+
+// Latest revision written by kine.
+const currentRevisionSQL = `SELECT COALESCE(MAX(id), 0) FROM kine`
+
+// Number of live keys, counting only the latest row of each key and skipping
+// kine's own compact_rev_key bookkeeping row.
+const keyCountSQL = `
+SELECT COUNT(*) FROM kine AS kv
+  JOIN (SELECT MAX(id) AS id FROM kine GROUP BY name) AS maxkv ON maxkv.id = kv.id
+ WHERE kv.deleted = 0 AND kv.name != 'compact_rev_key'`
+
+// Status returns the current status of this node.
+//
+// The node ID, address and uptime come from local state and are always set.
+// The remaining fields need the dqlite leader and are fetched within
+// leaderTimeout. If that fails, Leader is false, NumPeers falls back to the
+// local node store, CurrentRevision and KeyCount are left at zero, and Error
+// says what failed. This way the status is still available exactly when the
+// cluster is unhealthy.
+func (s *Server) Status(ctx context.Context) (*ServerStatus, error) {
+	status := &ServerStatus{
+		NodeID:        s.app.ID(),
+		Address:       s.app.Address(),
+		UptimeSeconds: int64(time.Since(s.started) / time.Second),
+	}
+
+	if err := s.leaderStatus(ctx, status); err != nil {
+		status.Leader = false
+		status.NumPeers = 0
+		status.CurrentRevision = 0
+		status.KeyCount = 0
+		status.Error = err.Error()
+		if nodes, err := s.localNodes(ctx); err == nil {
+			status.NumPeers = len(nodes) - 1
+		}
+	}
+
+	return status, nil
+}
+
+// Fill in the status fields that need the dqlite leader.
+func (s *Server) leaderStatus(ctx context.Context, status *ServerStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, leaderTimeout)
+	defer cancel()
+
+	cli, err := s.leader(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	leader, err := cli.Leader(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get leader")
+	}
+	status.Leader = leader != nil && leader.ID == s.app.ID()
+
+	nodes, err := cli.Cluster(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get cluster")
+	}
+	status.NumPeers = len(nodes) - 1
+
+	db, err := s.app.Open(ctx, database)
+	if err != nil {
+		return errors.Wrap(err, "open database")
+	}
+	defer db.Close()
+
+	if err := db.QueryRowContext(ctx, currentRevisionSQL).Scan(&status.CurrentRevision); err != nil {
+		return errors.Wrap(err, "query current revision")
+	}
+	if err := db.QueryRowContext(ctx, keyCountSQL).Scan(&status.KeyCount); err != nil {
+		return errors.Wrap(err, "query key count")
+	}
+
+	return nil
+}